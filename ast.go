@@ -0,0 +1,304 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/js"
+)
+
+var trackedHTTPMethods = map[string]bool{
+	"get": true, "post": true, "put": true, "delete": true, "patch": true,
+}
+
+func isTrackedCallee(name string) bool {
+	switch name {
+	case "fetch", "ajax", "request", "$.ajax", "XMLHttpRequest.open":
+		return true
+	}
+	parts := strings.Split(name, ".")
+	return len(parts) >= 2 && trackedHTTPMethods[parts[len(parts)-1]]
+}
+
+var apiShapeRe = regexp.MustCompile(`/v[0-9]+/`)
+
+func isAPIShaped(value string) bool {
+	return strings.Contains(value, "/api/") ||
+		strings.Contains(value, "/v1/") ||
+		strings.Contains(value, "/v2/") ||
+		apiShapeRe.MatchString(value)
+}
+
+func isURLish(value string) bool {
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") || strings.HasPrefix(value, "/")
+}
+
+// apiObjectKeys are the object-literal keys whose members are reported as
+// API Component findings (one per key:value pair) rather than as plain
+// "URL in variable" findings.
+var apiObjectKeys = map[string]bool{"endpoints": true, "routes": true, "api": true}
+
+// runASTDetectors walks a genuine JS AST (github.com/tdewolff/parse/v2/js),
+// looking for the same shapes the legacy variablePatterns/apiObjectPatterns/
+// apiCallPatterns regex families did, but resilient to template literals,
+// string concatenation and values split across lines. It returns false
+// (signalling the caller to fall back to regex) when jsContent doesn't parse
+// cleanly, which is common for obfuscated or corrupted minified bundles.
+func runASTDetectors(jsContent string, report func(name, match, details string)) bool {
+	ast, err := js.Parse(parse.NewInputString(jsContent), js.Options{})
+	if err != nil {
+		return false
+	}
+
+	js.Walk(&astVisitor{report: report}, ast)
+	return true
+}
+
+// astVisitor implements js.IVisitor, reporting a finding for each
+// variable declaration, object property and tracked call expression whose
+// value resolves to a URL or API-shaped path.
+type astVisitor struct {
+	report func(name, match, details string)
+}
+
+func (v *astVisitor) Enter(n js.INode) js.IVisitor {
+	switch node := n.(type) {
+	case *js.BindingElement:
+		v.visitBindingElement(node)
+	case *js.Property:
+		if !v.visitProperty(node) {
+			return nil
+		}
+	case *js.CallExpr:
+		v.visitCallExpr(node)
+	}
+	return v
+}
+
+func (v *astVisitor) Exit(js.INode) {}
+
+// visitBindingElement handles `const name = "...."` / `let name = `...“
+// declarations, mirroring the legacy variablePatterns regex family.
+func (v *astVisitor) visitBindingElement(b *js.BindingElement) {
+	if b.Default == nil {
+		return
+	}
+	name, ok := b.Binding.(*js.Var)
+	if !ok {
+		return
+	}
+
+	value, _, ok := evalExprValue(b.Default)
+	if !ok || !isURLish(value) {
+		return
+	}
+	v.report("URL in variable", value,
+		fmt.Sprintf("Found in JS variable %q - may contain sensitive API URLs (AST)", string(name.Name())))
+}
+
+// visitProperty handles `key: value` entries in object literals, mirroring
+// the legacy apiObjectPatterns regex family. It reports nested
+// endpoints/routes/api members as API Component findings and returns false
+// to stop the walker descending into that object again (it already visited
+// every member here), since the generic path below would otherwise re-visit
+// and double-report the same members as plain "URL in variable" findings.
+// Any other property whose value is a plain string/template literal falls
+// through to the generic catcher, whether or not its key is one of those
+// names - a string-valued "api" key is just as reportable as any other.
+func (v *astVisitor) visitProperty(p *js.Property) bool {
+	if p.Name == nil || p.Name.IsComputed() {
+		return true
+	}
+	key := string(p.Name.Literal.Data)
+
+	if obj, ok := p.Value.(*js.ObjectExpr); ok {
+		if apiObjectKeys[key] {
+			v.reportObjectMembers(key, obj)
+			return false
+		}
+		return true
+	}
+
+	value, _, ok := evalExprValue(p.Value)
+	if !ok || !isURLish(value) {
+		return true
+	}
+	v.report("URL in variable", value,
+		fmt.Sprintf("Found as object property %q - may contain sensitive API URLs (AST)", key))
+	return true
+}
+
+// reportObjectMembers reports each string-valued member of an
+// endpoints/routes/api object literal as a separate API Component finding.
+func (v *astVisitor) reportObjectMembers(parentKey string, obj *js.ObjectExpr) {
+	for _, member := range obj.List {
+		if member.Name == nil || member.Name.IsComputed() {
+			continue
+		}
+		childKey := string(member.Name.Literal.Data)
+
+		value, _, ok := evalExprValue(member.Value)
+		if !ok {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(value, "http"):
+			v.report("API Component", value, "API endpoint found in "+parentKey+" object definition - "+childKey+" (AST)")
+		case strings.HasPrefix(value, "/"):
+			v.report("API Component", value, "API path found in "+parentKey+" object definition - "+childKey+" (AST)")
+		}
+	}
+}
+
+// visitCallExpr handles calls to fetch/axios.*/$.ajax/XMLHttpRequest.open
+// and friends, mirroring the legacy apiCallPatterns regex family. Unlike the
+// regex version it follows simple string concatenation
+// (`axios.get(baseURL + "/v1/orders")`), reporting the identifiers it had to
+// substitute as placeholders.
+func (v *astVisitor) visitCallExpr(c *js.CallExpr) {
+	callee, ok := calleeName(c.X)
+	if !ok || !isTrackedCallee(callee) {
+		return
+	}
+
+	targetIdx := 0
+	if callee == "XMLHttpRequest.open" {
+		targetIdx = 1
+	}
+	if targetIdx >= len(c.Args.List) {
+		return
+	}
+
+	value, refs, ok := evalExprValue(c.Args.List[targetIdx].Value)
+	if !ok || value == "" {
+		return
+	}
+
+	details := ""
+	if len(refs) > 0 {
+		details = " (identifiers: " + strings.Join(refs, ", ") + ")"
+	}
+
+	switch {
+	case isAPIShaped(value):
+		v.report("API Endpoint", value, "API endpoint found in "+callee+" call (AST)"+details)
+	case strings.HasPrefix(value, "http"):
+		v.report("URL in variable", value, "URL found in "+callee+" call (AST)"+details)
+	}
+}
+
+// calleeName resolves a call expression's target to a dotted name such as
+// "fetch", "axios.get" or "XMLHttpRequest.open".
+func calleeName(expr js.IExpr) (string, bool) {
+	switch x := expr.(type) {
+	case *js.Var:
+		return string(x.Name()), true
+	case *js.DotExpr:
+		base, ok := calleeName(x.X)
+		if !ok {
+			return "", false
+		}
+		switch y := x.Y.(type) {
+		case js.LiteralExpr:
+			return base + "." + string(y.Data), true
+		case *js.LiteralExpr:
+			return base + "." + string(y.Data), true
+		case *js.Var:
+			return base + "." + string(y.Name()), true
+		}
+		return "", false
+	case *js.GroupExpr:
+		return calleeName(x.X)
+	}
+	return "", false
+}
+
+// evalExprValue folds a string literal, template literal or simple
+// string-concatenation expression into a single value, recording any
+// identifiers it had to substitute along the way. It returns ok=false for
+// anything else (numbers, nested calls, tagged templates, ...).
+func evalExprValue(expr js.IExpr) (value string, refs []string, ok bool) {
+	switch e := expr.(type) {
+	case *js.LiteralExpr:
+		if e.TokenType != js.StringToken {
+			return "", nil, false
+		}
+		return unquoteJS(e.Data), nil, true
+
+	case *js.TemplateExpr:
+		if e.Tag != nil {
+			return "", nil, false
+		}
+		value, refs := renderTemplate(e)
+		return value, refs, true
+
+	case *js.BinaryExpr:
+		if e.Op != js.AddToken {
+			return "", nil, false
+		}
+		lv, lrefs, lok := evalConcatOperand(e.X)
+		rv, rrefs, rok := evalConcatOperand(e.Y)
+		if !lok || !rok {
+			return "", nil, false
+		}
+		return lv + rv, append(lrefs, rrefs...), true
+
+	case *js.GroupExpr:
+		return evalExprValue(e.X)
+	}
+
+	return "", nil, false
+}
+
+// evalConcatOperand evaluates one side of a "+" string concatenation,
+// additionally accepting a bare identifier reference (folded into a
+// "${name}" placeholder) which evalExprValue alone doesn't handle.
+func evalConcatOperand(expr js.IExpr) (string, []string, bool) {
+	if v, ok := expr.(*js.Var); ok {
+		name := string(v.Name())
+		return "${" + name + "}", []string{name}, true
+	}
+	return evalExprValue(expr)
+}
+
+// unquoteJS strips the surrounding quote characters from a raw string
+// literal token (its escape sequences, if any, are left untouched).
+func unquoteJS(data []byte) string {
+	if len(data) < 2 {
+		return string(data)
+	}
+	return string(data[1 : len(data)-1])
+}
+
+// renderTemplate folds a template literal's literal parts and embedded
+// identifier references into a single value plus the list of identifiers
+// substituted, e.g. `https://example.com/api/${id}` -> ("https://example.
+// com/api/${id}", ["id"]).
+func renderTemplate(t *js.TemplateExpr) (string, []string) {
+	var sb strings.Builder
+	var refs []string
+
+	for _, part := range t.List {
+		sb.WriteString(trimTemplateDelims(part.Value))
+		if name, ok := calleeName(part.Expr); ok {
+			refs = append(refs, name)
+			sb.WriteString("${" + name + "}")
+		}
+	}
+	sb.WriteString(trimTemplateDelims(t.Tail))
+
+	return sb.String(), refs
+}
+
+// trimTemplateDelims strips the backtick/`${`/`}` delimiters tdewolff/parse
+// leaves attached to each TemplatePart.Value and TemplateExpr.Tail.
+func trimTemplateDelims(b []byte) string {
+	s := string(b)
+	s = strings.TrimPrefix(s, "`")
+	s = strings.TrimPrefix(s, "}")
+	s = strings.TrimSuffix(s, "${")
+	s = strings.TrimSuffix(s, "`")
+	return s
+}
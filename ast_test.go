@@ -0,0 +1,73 @@
+package main
+
+import "testing"
+
+type astFinding struct {
+	name, match, details string
+}
+
+func collectASTFindings(t *testing.T, src string) []astFinding {
+	t.Helper()
+
+	var findings []astFinding
+	if ok := runASTDetectors(src, func(name, match, details string) {
+		findings = append(findings, astFinding{name, match, details})
+	}); !ok {
+		t.Fatalf("runASTDetectors: failed to parse %q", src)
+	}
+	return findings
+}
+
+func TestRunASTDetectorsVariableDeclaration(t *testing.T) {
+	findings := collectASTFindings(t, `const apiUrl = "https://example.com/api/v1/users";`)
+	if len(findings) != 1 || findings[0].match != "https://example.com/api/v1/users" {
+		t.Fatalf("unexpected findings: %+v", findings)
+	}
+}
+
+func TestRunASTDetectorsObjectProperty(t *testing.T) {
+	findings := collectASTFindings(t, `const config = { api: "https://example.com/special-gateway/users" };`)
+	if len(findings) != 1 || findings[0].name != "URL in variable" {
+		t.Fatalf("unexpected findings: %+v", findings)
+	}
+}
+
+func TestRunASTDetectorsEndpointsObject(t *testing.T) {
+	findings := collectASTFindings(t, `const config = { endpoints: { users: "/api/v1/users" } };`)
+	if len(findings) != 1 || findings[0].name != "API Component" || findings[0].match != "/api/v1/users" {
+		t.Fatalf("unexpected findings: %+v", findings)
+	}
+}
+
+// TestRunASTDetectorsDottedCall is a regression test for calleeName:
+// tdewolff/parse/v2/js stores a DotExpr's member name as a value
+// js.LiteralExpr, never a pointer, so matching only on *js.LiteralExpr left
+// every dotted call unresolved and silently undetected in the default
+// -mode ast.
+func TestRunASTDetectorsDottedCall(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"axios.get", `axios.get("http://x.com/special-gateway/y");`, "http://x.com/special-gateway/y"},
+		{"$.ajax", `$.ajax("http://x.com/special-gateway/z");`, "http://x.com/special-gateway/z"},
+		{"XMLHttpRequest.open", `XMLHttpRequest.open("GET", "http://x.com/special-gateway/w");`, "http://x.com/special-gateway/w"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := collectASTFindings(t, tt.src)
+			if len(findings) != 1 || findings[0].match != tt.want {
+				t.Fatalf("unexpected findings: %+v", findings)
+			}
+		})
+	}
+}
+
+func TestRunASTDetectorsCallConcatenation(t *testing.T) {
+	findings := collectASTFindings(t, `axios.get(baseURL + "/v1/orders");`)
+	if len(findings) != 1 || findings[0].name != "API Endpoint" || findings[0].match != "${baseURL}/v1/orders" {
+		t.Fatalf("unexpected findings: %+v", findings)
+	}
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	crawlDepth int
+	sameHost   bool
+
+	// inlineScripts holds the bodies of <script> blocks discovered while
+	// crawling HTML pages, keyed by the synthetic source label they were
+	// queued under (e.g. "https://site.com/page.html#inline-1"), since they
+	// have no URL of their own for analyzeJSFile to fetch.
+	inlineScripts = make(map[string]string)
+)
+
+var (
+	scriptTagRe = regexp.MustCompile(`(?i)<script\b[^>]*>`)
+	scriptSrcRe = regexp.MustCompile(`(?i)\bsrc\s*=\s*["']([^"']+)["']`)
+	linkTagRe   = regexp.MustCompile(`(?i)<link\b[^>]*>`)
+	linkHrefRe  = regexp.MustCompile(`(?i)\bhref\s*=\s*["']([^"']+)["']`)
+	inlineRe    = regexp.MustCompile(`(?is)<script\b([^>]*)>(.*?)</script>`)
+)
+
+// expandSeeds turns the raw -f/-i/pipe input list into a final list of JS
+// sources to analyze, following HTML pages (<script src>, <link
+// rel=preload as=script>, inline <script> blocks) up to -depth hops.
+func expandSeeds(seeds []string) []string {
+	visited := make(map[string]bool)
+	var expanded []string
+
+	for _, seed := range seeds {
+		found, err := discoverJSSources(seed, crawlDepth, visited)
+		if err != nil {
+			printer.PrintError(seed, err.Error())
+			continue
+		}
+		expanded = append(expanded, found...)
+	}
+
+	return expanded
+}
+
+// discoverJSSources resolves seed into one or more JS sources. If seed is
+// itself JS (by content, not just extension) it is returned unchanged; if
+// it's an HTML page, its scripts are extracted and, for any that turn out
+// to be HTML themselves, followed recursively while remainingDepth allows.
+func discoverJSSources(seed string, remainingDepth int, visited map[string]bool) ([]string, error) {
+	if visited[seed] {
+		return nil, nil
+	}
+	visited[seed] = true
+
+	body, contentType, err := fetchSource(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	if !looksLikeHTML(contentType, body) {
+		return []string{seed}, nil
+	}
+
+	if !pipeMode {
+		printer.PrintStatus(fmt.Sprintf("Crawling HTML page: %s (depth remaining %d)", seed, remainingDepth))
+	}
+
+	var sources []string
+
+	for _, ref := range extractScriptRefs(body) {
+		resolved, err := resolveReference(seed, ref)
+		if err != nil {
+			continue
+		}
+		if sameHost && !sameHostAs(seed, resolved) {
+			continue
+		}
+
+		if remainingDepth > 0 {
+			nested, err := discoverJSSources(resolved, remainingDepth-1, visited)
+			if err != nil {
+				printer.PrintError(resolved, err.Error())
+				continue
+			}
+			sources = append(sources, nested...)
+		} else {
+			sources = append(sources, resolved)
+		}
+	}
+
+	for i, inline := range extractInlineScripts(body) {
+		label := fmt.Sprintf("%s#inline-%d", seed, i+1)
+		inlineScripts[label] = inline
+		sources = append(sources, label)
+	}
+
+	return sources, nil
+}
+
+// extractScriptRefs pulls every <script src=...> and
+// <link rel=preload as=script href=...> reference out of an HTML page.
+func extractScriptRefs(body []byte) []string {
+	var refs []string
+
+	for _, tag := range scriptTagRe.FindAllString(string(body), -1) {
+		if m := scriptSrcRe.FindStringSubmatch(tag); m != nil {
+			refs = append(refs, m[1])
+		}
+	}
+
+	for _, tag := range linkTagRe.FindAllString(string(body), -1) {
+		lower := strings.ToLower(tag)
+		if !strings.Contains(lower, "rel=\"preload\"") && !strings.Contains(lower, "rel='preload'") {
+			continue
+		}
+		if !strings.Contains(lower, "as=\"script\"") && !strings.Contains(lower, "as='script'") {
+			continue
+		}
+		if m := linkHrefRe.FindStringSubmatch(tag); m != nil {
+			refs = append(refs, m[1])
+		}
+	}
+
+	return refs
+}
+
+// extractInlineScripts returns the bodies of <script> blocks with no src
+// attribute.
+func extractInlineScripts(body []byte) []string {
+	var inline []string
+	for _, m := range inlineRe.FindAllStringSubmatch(string(body), -1) {
+		attrs, script := m[1], m[2]
+		if scriptSrcRe.MatchString(attrs) {
+			continue
+		}
+		if strings.TrimSpace(script) == "" {
+			continue
+		}
+		inline = append(inline, script)
+	}
+	return inline
+}
+
+// looksLikeHTML decides, from Content-Type and a body sniff, whether a
+// fetched resource is an HTML page rather than JS.
+func looksLikeHTML(contentType string, body []byte) bool {
+	if strings.Contains(strings.ToLower(contentType), "text/html") {
+		return true
+	}
+
+	sniffLen := len(body)
+	if sniffLen > 1024 {
+		sniffLen = 1024
+	}
+	lower := strings.ToLower(strings.TrimSpace(string(body[:sniffLen])))
+	return strings.HasPrefix(lower, "<!doctype html") || strings.Contains(lower, "<html")
+}
+
+// sameHostAs reports whether target resolves to the same host as base.
+// Relative/local references always pass.
+func sameHostAs(base, target string) bool {
+	if !strings.HasPrefix(target, "http") {
+		return true
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return true
+	}
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return true
+	}
+	return strings.EqualFold(baseURL.Hostname(), targetURL.Hostname())
+}
+
+// resolveReference resolves ref (found inside base, which may be a remote
+// URL or a local file path) to an absolute URL or filesystem path.
+func resolveReference(base, ref string) (string, error) {
+	if strings.HasPrefix(ref, "http") {
+		return ref, nil
+	}
+	if strings.HasPrefix(base, "http") {
+		baseURL, err := url.Parse(base)
+		if err != nil {
+			return "", err
+		}
+		refURL, err := url.Parse(ref)
+		if err != nil {
+			return "", err
+		}
+		return baseURL.ResolveReference(refURL).String(), nil
+	}
+	return filepath.Join(filepath.Dir(base), ref), nil
+}
+
+// fetchSource reads a seed (remote URL or local path) and returns its body
+// plus Content-Type header, if any.
+func fetchSource(source string) ([]byte, string, error) {
+	if !strings.HasPrefix(source, "http") {
+		body, err := os.ReadFile(source)
+		return body, "", err
+	}
+
+	client := &http.Client{Timeout: 15 * time.Second}
+
+	req, err := http.NewRequest("GET", source, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("error fetching %s: %w", source, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading %s: %w", source, err)
+	}
+
+	return body, resp.Header.Get("Content-Type"), nil
+}
+
+// sourceMappingURLRe matches the trailing sourceMappingURL comment that
+// bundlers append to production JS output.
+var sourceMappingURLRe = regexp.MustCompile(`//[#@]\s*sourceMappingURL=(\S+)`)
+
+type sourceMapFile struct {
+	Sources        []string `json:"sources"`
+	SourcesContent []string `json:"sourcesContent"`
+}
+
+// analyzeSourceMap follows a JS file's //# sourceMappingURL, if any, and
+// analyzes each original source it contains separately - source maps in
+// production bundles regularly leak internal API paths that the minified
+// bundle hides behind mangled identifiers.
+func analyzeSourceMap(jsURL, jsContent string, resultsChan chan<- Result) {
+	matches := sourceMappingURLRe.FindAllStringSubmatch(jsContent, -1)
+	if len(matches) == 0 {
+		return
+	}
+	mapRef := matches[len(matches)-1][1]
+
+	mapURL, err := resolveReference(jsURL, mapRef)
+	if err != nil {
+		return
+	}
+
+	data, _, err := fetchSource(mapURL)
+	if err != nil {
+		printer.PrintError(mapURL, fmt.Sprintf("failed to fetch source map: %v", err))
+		return
+	}
+
+	var sm sourceMapFile
+	if err := json.Unmarshal(data, &sm); err != nil {
+		printer.PrintError(mapURL, fmt.Sprintf("invalid source map: %v", err))
+		return
+	}
+
+	for i, src := range sm.Sources {
+		if i >= len(sm.SourcesContent) || sm.SourcesContent[i] == "" {
+			continue
+		}
+		label := fmt.Sprintf("%s (source map: %s)", jsURL, src)
+		if !pipeMode {
+			printer.PrintStatus("Analyzing original source from map: " + label)
+		}
+		analyzeJSContent(label, sm.SourcesContent[i], resultsChan)
+	}
+}
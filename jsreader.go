@@ -23,24 +23,33 @@ const (
 	colorCyan    = "\033[36m"
 	colorWhite   = "\033[37m"
 	colorOrange  = "\033[38;5;208m"
-	colorTeal    = "\033[38;5;6m" 
-	colorPink    = "\033[38;5;13m" 
-	colorMagenta = "\033[35m"  
+	colorTeal    = "\033[38;5;6m"
+	colorPink    = "\033[38;5;13m"
+	colorMagenta = "\033[35m"
+
+	jsReaderVersion = "1.0.0"
 )
 
 var (
-	threads    int
-	inputFile  string
-	jsFile     string
-	pipeMode   bool
-	outputFile string
+	threads      int
+	inputFile    string
+	jsFile       string
+	pipeMode     bool
+	outputFile   string
+	templateDir  string
+	tagFilter    string
+	outputFormat string
+	analysisMode string
+
+	loadedTemplates []*PatternTemplate
 )
 
 type Result struct {
-	Type    string
-	URL     string
-	Source  string
-	Details string
+	Type       string `json:"type"`
+	URL        string `json:"url"`
+	Source     string `json:"source"`
+	Details    string `json:"details"`
+	Validation string `json:"validation"`
 }
 
 type SafePrinter struct {
@@ -105,6 +114,11 @@ func (p *SafePrinter) PrintResult(result Result) {
 			colorWhite, colorReset, result.Details)
 	}
 
+	if result.Validation != "" {
+		fmt.Printf("   %sValidation:%s %s\n",
+			colorWhite, colorReset, result.Validation)
+	}
+
 	if result.Source != "" {
 		fmt.Printf("   %sSource:%s %s\n\n",
 			colorWhite, colorReset, result.Source)
@@ -120,6 +134,9 @@ func (p *SafePrinter) PrintResult(result Result) {
 		if result.Details != "" {
 			entry += fmt.Sprintf("Details: %s\n", result.Details)
 		}
+		if result.Validation != "" {
+			entry += fmt.Sprintf("Validation: %s\n", result.Validation)
+		}
 		if result.Source != "" {
 			entry += fmt.Sprintf("Source: %s\n", result.Source)
 		}
@@ -132,16 +149,18 @@ func (p *SafePrinter) PrintResult(result Result) {
 	}
 }
 
+// PrintStatus and PrintError always write to stderr, so stdout stays clean
+// for -format json/ndjson/sarif output that's meant to be piped elsewhere.
 func (p *SafePrinter) PrintStatus(msg string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	fmt.Printf("%s[STATUS]%s %s\n", colorBlue, colorReset, msg)
+	fmt.Fprintf(os.Stderr, "%s[STATUS]%s %s\n", colorBlue, colorReset, msg)
 }
 
 func (p *SafePrinter) PrintError(source, msg string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	fmt.Printf("%s[ERROR]%s %s - %s\n", colorRed, colorReset, source, msg)
+	fmt.Fprintf(os.Stderr, "%s[ERROR]%s %s - %s\n", colorRed, colorReset, source, msg)
 }
 
 func (p *SafePrinter) CloseOutput() {
@@ -158,21 +177,58 @@ func main() {
 	flag.StringVar(&jsFile, "f", "", "Path to single JS file to analyze")
 	flag.BoolVar(&pipeMode, "p", false, "Enable pipe mode (read from stdin)")
 	flag.StringVar(&outputFile, "o", "", "Output file to save results (.txt)")
+	flag.StringVar(&templateDir, "templates", "./patterns", "Directory of YAML pattern templates")
+	flag.StringVar(&tagFilter, "tag", "", "Only run templates matching severity:<level> or tags:<tag>")
+	flag.BoolVar(&validateEnabled, "validate", false, "Actively verify findings (Telegram, S3, Firebase, endpoints)")
+	flag.Float64Var(&validateRPS, "validate-rps", 2, "Max validation requests per second against targets")
+	flag.IntVar(&crawlDepth, "depth", 1, "Hops of <script src> following allowed when a seed is an HTML page")
+	flag.BoolVar(&sameHost, "same-host", false, "Don't follow discovered scripts onto a different host (avoids drifting onto CDNs)")
+	flag.StringVar(&outputFormat, "format", "text", "Output format: text|json|ndjson|sarif")
+	flag.StringVar(&analysisMode, "mode", "ast", "Variable/object/call-expression analysis: ast|regex|both")
 	flag.Parse()
 
-	if outputFile != "" {
+	templates, err := loadTemplates(templateDir)
+	if err != nil {
+		printer.PrintError("Templates", err.Error())
+		os.Exit(1)
+	}
+	templates = filterTemplates(templates, tagFilter)
+	if len(templates) == 0 {
+		printer.PrintError("Templates", "No pattern templates matched; nothing to scan for")
+		os.Exit(1)
+	}
+	loadedTemplates = templates
+
+	var reportOut io.Writer = os.Stdout
+	if outputFormat == "" || outputFormat == "text" {
+		if outputFile != "" {
+			fh, err := os.Create(outputFile)
+			if err != nil {
+				printer.PrintError("Output", fmt.Sprintf("Failed to create output file: %v", err))
+				os.Exit(1)
+			}
+			printer.outputFH = fh
+			defer printer.CloseOutput()
+
+			_, err = fh.WriteString("=== JS Parser Results ===\n\n")
+			if err != nil {
+				printer.PrintError("Output", fmt.Sprintf("Failed to write to output file: %v", err))
+			}
+		}
+	} else if outputFile != "" {
 		fh, err := os.Create(outputFile)
 		if err != nil {
 			printer.PrintError("Output", fmt.Sprintf("Failed to create output file: %v", err))
 			os.Exit(1)
 		}
-		printer.outputFH = fh
-		defer printer.CloseOutput()
+		defer fh.Close()
+		reportOut = fh
+	}
 
-		_, err = fh.WriteString("=== JS Parser Results ===\n\n")
-		if err != nil {
-			printer.PrintError("Output", fmt.Sprintf("Failed to write to output file: %v", err))
-		}
+	reporter, err := newReporter(outputFormat, reportOut)
+	if err != nil {
+		printer.PrintError("Format", err.Error())
+		os.Exit(1)
 	}
 
 	stat, _ := os.Stdin.Stat()
@@ -222,6 +278,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	jsFiles = expandSeeds(jsFiles)
+	if len(jsFiles) == 0 {
+		printer.PrintError("Input", "No JS files discovered")
+		os.Exit(1)
+	}
+
 	if !pipeMode {
 		printer.PrintStatus(fmt.Sprintf("Found %d JS files to analyze", len(jsFiles)))
 		printer.PrintStatus(fmt.Sprintf("Using %d threads", threads))
@@ -244,9 +306,11 @@ func main() {
 		}()
 	}
 
+	reportingDone := make(chan struct{})
 	go func() {
+		defer close(reportingDone)
 		for result := range resultsChan {
-			printer.PrintResult(result)
+			reporter.ReportResult(result)
 		}
 	}()
 
@@ -257,13 +321,21 @@ func main() {
 
 	wg.Wait()
 	close(resultsChan)
+	<-reportingDone
+
+	if err := reporter.Finish(); err != nil {
+		printer.PrintError("Report", err.Error())
+		os.Exit(1)
+	}
 }
 
 func analyzeJSFile(jsURL string, resultsChan chan<- Result) {
 	var content []byte
 	var err error
 
-	if strings.HasPrefix(jsURL, "http") {
+	if inline, ok := inlineScripts[jsURL]; ok {
+		content = []byte(inline)
+	} else if strings.HasPrefix(jsURL, "http") {
 		if !pipeMode {
 			printer.PrintStatus(fmt.Sprintf("Fetching remote file: %s", jsURL))
 		}
@@ -284,20 +356,24 @@ func analyzeJSFile(jsURL string, resultsChan chan<- Result) {
 		printer.PrintStatus(fmt.Sprintf("Analyzing %s (%d bytes)", jsURL, len(content)))
 	}
 
-	jsContent := string(content)
+	analyzeJSContent(jsURL, string(content), resultsChan)
+	analyzeSourceMap(jsURL, string(content), resultsChan)
+}
 
+// analyzeJSContent runs every detection (templates + the specialized regex
+// families below) against jsContent, reporting findings tagged with
+// sourceLabel. It is split out from analyzeJSFile so source-mapped original
+// sources and inline <script> bodies can be analyzed without re-fetching.
+func analyzeJSContent(sourceLabel string, jsContent string, resultsChan chan<- Result) {
+	jsURL := sourceLabel
+
+	// S3, Firebase, API/GraphQL/Auth endpoints, API subdomains and Telegram
+	// tokens are now driven by loadedTemplates (see templates.go). Only the
+	// quoted-literal detections below don't fit the template model, since
+	// they report a captured group rather than the whole match.
 	patterns := map[string]*regexp.Regexp{
-		"S3 Bucket":        regexp.MustCompile(`https?://[a-zA-Z0-9.-]*\.?s3[.-][a-z0-9-]*\.amazonaws\.com[^\s"']*`),
-		"Firebase URL":     regexp.MustCompile(`https?://[a-zA-Z0-9-]+\.firebaseio\.com[^\s"']*`),
-		"Firebase Storage": regexp.MustCompile(`https?://firebasestorage\.googleapis\.com[^\s"']*`),
-		"Firebase API":     regexp.MustCompile(`https?://[a-zA-Z0-9-]+\.firebaseapp\.com[^\s"']*`),
-		"API Endpoint":     regexp.MustCompile(`https?://[a-zA-Z0-9.-]+/(v[0-9]+/|api/)[a-zA-Z0-9./_-]*`),
-		"GraphQL":          regexp.MustCompile(`https?://[a-zA-Z0-9.-]+/(graphql|gql)[^\s"']*`),
-		"Auth Endpoint":    regexp.MustCompile(`https?://[a-zA-Z0-9.-]+/(auth|oauth|token|login|register|user|admin)[^\s"']*`),
-		"Telegram Token":   regexp.MustCompile(`[0-9]{8,10}:[a-zA-Z0-9_-]{35}`),
-		"API Version":      regexp.MustCompile(`["'](v[0-9]+(\.[0-9]+)?)["']`),
-		"API Subdomain":    regexp.MustCompile(`https?://(api|api-[a-zA-Z0-9]+)\.([a-zA-Z0-9-]+\.)+[a-zA-Z]{2,}[^\s"']*`),
-		"API Component":    regexp.MustCompile(`["'](/(api|rest|v[0-9]+)/[a-zA-Z0-9/_-]+)["']`),
+		"API Version":   regexp.MustCompile(`["'](v[0-9]+(\.[0-9]+)?)["']`),
+		"API Component": regexp.MustCompile(`["'](/(api|rest|v[0-9]+)/[a-zA-Z0-9/_-]+)["']`),
 	}
 
 	excludePatterns := []*regexp.Regexp{
@@ -319,16 +395,25 @@ func analyzeJSFile(jsURL string, resultsChan chan<- Result) {
 		}
 
 		mu.Lock()
-		defer mu.Unlock()
-		if !found[match] {
-			resultsChan <- Result{
-				Type:    name,
-				URL:     match,
-				Source:  jsURL,
-				Details: details,
-			}
+		alreadyFound := found[match]
+		if !alreadyFound {
 			found[match] = true
 		}
+		mu.Unlock()
+		if alreadyFound {
+			return
+		}
+
+		result := Result{
+			Type:    name,
+			URL:     match,
+			Source:  jsURL,
+			Details: details,
+		}
+		if validateEnabled {
+			result.Validation = validateResult(result)
+		}
+		resultsChan <- result
 	}
 
 	for name, re := range patterns {
@@ -336,20 +421,8 @@ func analyzeJSFile(jsURL string, resultsChan chan<- Result) {
 		for _, match := range matches {
 			details := ""
 			switch name {
-			case "S3 Bucket":
-				details = "Potential public S3 bucket - check permissions"
-			case "Firebase URL", "Firebase Storage", "Firebase API":
-				details = "Firebase service - check security rules"
-			case "Auth Endpoint":
-				details = "Authentication endpoint - check for vulnerabilities"
-			case "API Endpoint":
-				details = "API endpoint - investigate available methods"
-			case "Telegram Token":
-				details = "Telegram Bot API token - check if it's exposed"
 			case "API Version":
 				details = "API version identifier - may indicate available API versions"
-			case "API Subdomain":
-				details = "Dedicated API subdomain - investigate available endpoints"
 			case "API Component":
 				details = "API path component - may indicate service structure"
 			}
@@ -357,6 +430,31 @@ func analyzeJSFile(jsURL string, resultsChan chan<- Result) {
 		}
 	}
 
+	for _, t := range loadedTemplates {
+		// context-regex runs first so that a match it upgrades to "high
+		// confidence" lands in found[] with that detail text; the plain
+		// regex pass below then silently no-ops on the same match instead
+		// of overwriting it with the lower-confidence version.
+		if t.compiledContext != nil {
+			for _, m := range t.compiledContext.FindAllStringSubmatch(jsContent, -1) {
+				value := m[len(m)-1]
+				if t.excluded(value) {
+					continue
+				}
+				reportFinding(t.Type, value, t.Details+" (context match - high confidence)")
+			}
+		}
+
+		for _, re := range t.compiledRegex {
+			for _, match := range re.FindAllString(jsContent, -1) {
+				if t.excluded(match) {
+					continue
+				}
+				reportFinding(t.Type, match, t.Details)
+			}
+		}
+	}
+
 	apiVersionRe := regexp.MustCompile(`https?://[^/]+/v([0-9]+(\.[0-9]+)?)/`)
 	apiVersionMatches := apiVersionRe.FindAllStringSubmatch(jsContent, -1)
 	for _, match := range apiVersionMatches {
@@ -367,79 +465,91 @@ func analyzeJSFile(jsURL string, resultsChan chan<- Result) {
 		}
 	}
 
-	telegramContextRe := regexp.MustCompile(`(bot|token|api|key)[\s]*[=:][\s]*["']([0-9]{8,10}:[a-zA-Z0-9_-]{35})["']`)
-	telegramContextMatches := telegramContextRe.FindAllStringSubmatch(jsContent, -1)
-	for _, match := range telegramContextMatches {
-		if len(match) > 2 {
-			details := "Telegram Bot API token in a variable context - high confidence match"
-			reportFinding("Telegram Token", match[2], details)
+	apiVersionCommentRe := regexp.MustCompile(`\/\/.*\b(v[0-9]+(\.[0-9]+)?)\b.*api`)
+	apiVersionCommentMatches := apiVersionCommentRe.FindAllStringSubmatch(jsContent, -1)
+	for _, match := range apiVersionCommentMatches {
+		if len(match) > 1 {
+			reportFinding("API Version", match[1], "API version mentioned in code comment")
 		}
 	}
 
-	variablePatterns := []*regexp.Regexp{
-		regexp.MustCompile(`const\s+[a-zA-Z0-9_]+\s*=\s*["'](https?://[^"'\s]+)["']`),
-		regexp.MustCompile(`let\s+[a-zA-Z0-9_]+\s*=\s*["'](https?://[^"'\s]+)["']`),
-		regexp.MustCompile(`var\s+[a-zA-Z0-9_]+\s*=\s*["'](https?://[^"'\s]+)["']`),
-		regexp.MustCompile(`[a-zA-Z0-9_]+\s*:\s*["'](https?://[^"'\s]+)["']`),
-		regexp.MustCompile(`(url|endpoint|api|baseUrl|apiUrl|baseURL|apiURL)\s*[=:]\s*["'](https?://[^"'\s]+)["']`),
-		regexp.MustCompile(`(url|endpoint|api|baseUrl|apiUrl|baseURL|apiURL)\s*[=:]\s*["'](\/[^"'\s]+)["']`),
-	}
-
-	for _, re := range variablePatterns {
-		matches := re.FindAllStringSubmatch(jsContent, -1)
-		for _, match := range matches {
-			if len(match) > 1 {
-				reportFinding("URL in variable", match[1], "Found in JavaScript variable - may contain sensitive API URLs")
+	// The variable/object/call-expression families below miss template
+	// literals, concatenation and computed property names when done by
+	// regex alone, so -mode ast walks a parsed JS AST instead (see ast.go).
+	// The AST pass handles template literals and concatenation; properties
+	// with computed names are skipped by it and not reported.
+	// -mode regex keeps the original regexes; -mode both runs both; ast
+	// mode falls back to regex automatically if parsing fails, which is
+	// common on obfuscated/minified bundles.
+	useRegex := analysisMode == "regex" || analysisMode == "both"
+
+	if analysisMode == "ast" || analysisMode == "both" {
+		if !runASTDetectors(jsContent, reportFinding) {
+			if !pipeMode {
+				printer.PrintStatus(fmt.Sprintf("AST parse failed for %s, falling back to regex", jsURL))
 			}
+			useRegex = true
 		}
 	}
 
-	apiObjectPatterns := []*regexp.Regexp{
-		regexp.MustCompile(`endpoints\s*:\s*\{\s*[^}]*["']([^"']+)["']\s*:\s*["']([^"']+)["']`),
-		regexp.MustCompile(`api\s*:\s*\{\s*[^}]*["']([^"']+)["']\s*:\s*["']([^"']+)["']`),
-		regexp.MustCompile(`routes\s*:\s*\{\s*[^}]*["']([^"']+)["']\s*:\s*["']([^"']+)["']`),
-	}
+	if useRegex {
+		variablePatterns := []*regexp.Regexp{
+			regexp.MustCompile(`const\s+[a-zA-Z0-9_]+\s*=\s*["'](https?://[^"'\s]+)["']`),
+			regexp.MustCompile(`let\s+[a-zA-Z0-9_]+\s*=\s*["'](https?://[^"'\s]+)["']`),
+			regexp.MustCompile(`var\s+[a-zA-Z0-9_]+\s*=\s*["'](https?://[^"'\s]+)["']`),
+			regexp.MustCompile(`[a-zA-Z0-9_]+\s*:\s*["'](https?://[^"'\s]+)["']`),
+			regexp.MustCompile(`(url|endpoint|api|baseUrl|apiUrl|baseURL|apiURL)\s*[=:]\s*["'](https?://[^"'\s]+)["']`),
+			regexp.MustCompile(`(url|endpoint|api|baseUrl|apiUrl|baseURL|apiURL)\s*[=:]\s*["'](\/[^"'\s]+)["']`),
+		}
 
-	for _, re := range apiObjectPatterns {
-		matches := re.FindAllStringSubmatch(jsContent, -1)
-		for _, match := range matches {
-			if len(match) > 2 {
-				endpoint := match[2]
-				if strings.HasPrefix(endpoint, "http") {
-					reportFinding("API Component", endpoint, "API endpoint found in object definition - "+match[1])
-				} else if strings.HasPrefix(endpoint, "/") {
-					reportFinding("API Component", endpoint, "API path found in object definition - "+match[1])
+		for _, re := range variablePatterns {
+			matches := re.FindAllStringSubmatch(jsContent, -1)
+			for _, match := range matches {
+				if len(match) > 1 {
+					reportFinding("URL in variable", match[1], "Found in JavaScript variable - may contain sensitive API URLs")
 				}
 			}
 		}
-	}
 
-	apiVersionCommentRe := regexp.MustCompile(`\/\/.*\b(v[0-9]+(\.[0-9]+)?)\b.*api`)
-	apiVersionCommentMatches := apiVersionCommentRe.FindAllStringSubmatch(jsContent, -1)
-	for _, match := range apiVersionCommentMatches {
-		if len(match) > 1 {
-			reportFinding("API Version", match[1], "API version mentioned in code comment")
+		apiObjectPatterns := []*regexp.Regexp{
+			regexp.MustCompile(`endpoints\s*:\s*\{\s*[^}]*["']([^"']+)["']\s*:\s*["']([^"']+)["']`),
+			regexp.MustCompile(`api\s*:\s*\{\s*[^}]*["']([^"']+)["']\s*:\s*["']([^"']+)["']`),
+			regexp.MustCompile(`routes\s*:\s*\{\s*[^}]*["']([^"']+)["']\s*:\s*["']([^"']+)["']`),
 		}
-	}
 
-	apiCallPatterns := []*regexp.Regexp{
-		regexp.MustCompile(`(fetch|axios\.get|axios\.post|ajax|request)\s*\(\s*["'](https?://[^"'\s]+)["']`),
-		regexp.MustCompile(`\.(get|post|put|delete|patch)\s*\(\s*["'](https?://[^"'\s]+)["']`),
-		regexp.MustCompile(`\.(get|post|put|delete|patch)\s*\(\s*["'](\/[^"'\s]+)["']`),
-	}
+		for _, re := range apiObjectPatterns {
+			matches := re.FindAllStringSubmatch(jsContent, -1)
+			for _, match := range matches {
+				if len(match) > 2 {
+					endpoint := match[2]
+					if strings.HasPrefix(endpoint, "http") {
+						reportFinding("API Component", endpoint, "API endpoint found in object definition - "+match[1])
+					} else if strings.HasPrefix(endpoint, "/") {
+						reportFinding("API Component", endpoint, "API path found in object definition - "+match[1])
+					}
+				}
+			}
+		}
 
-	for _, re := range apiCallPatterns {
-		matches := re.FindAllStringSubmatch(jsContent, -1)
-		for _, match := range matches {
-			if len(match) > 2 {
-				endpoint := match[2]
-				if strings.Contains(endpoint, "/api/") ||
-					strings.Contains(endpoint, "/v1/") ||
-					strings.Contains(endpoint, "/v2/") ||
-					regexp.MustCompile(`/v[0-9]+/`).MatchString(endpoint) {
-					reportFinding("API Endpoint", endpoint, "API endpoint found in "+match[1]+" call")
-				} else if strings.HasPrefix(endpoint, "http") {
-					reportFinding("URL in variable", endpoint, "URL found in "+match[1]+" call")
+		apiCallPatterns := []*regexp.Regexp{
+			regexp.MustCompile(`(fetch|axios\.get|axios\.post|ajax|request)\s*\(\s*["'](https?://[^"'\s]+)["']`),
+			regexp.MustCompile(`\.(get|post|put|delete|patch)\s*\(\s*["'](https?://[^"'\s]+)["']`),
+			regexp.MustCompile(`\.(get|post|put|delete|patch)\s*\(\s*["'](\/[^"'\s]+)["']`),
+		}
+
+		for _, re := range apiCallPatterns {
+			matches := re.FindAllStringSubmatch(jsContent, -1)
+			for _, match := range matches {
+				if len(match) > 2 {
+					endpoint := match[2]
+					if strings.Contains(endpoint, "/api/") ||
+						strings.Contains(endpoint, "/v1/") ||
+						strings.Contains(endpoint, "/v2/") ||
+						regexp.MustCompile(`/v[0-9]+/`).MatchString(endpoint) {
+						reportFinding("API Endpoint", endpoint, "API endpoint found in "+match[1]+" call")
+					} else if strings.HasPrefix(endpoint, "http") {
+						reportFinding("URL in variable", endpoint, "URL found in "+match[1]+" call")
+					}
 				}
 			}
 		}
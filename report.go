@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Reporter is how a finished Result reaches the outside world. Selecting
+// -format wires up the matching implementation so TextReporter,
+// JSONReporter, NDJSONReporter and SARIFReporter can all coexist.
+type Reporter interface {
+	ReportResult(result Result)
+	Finish() error
+}
+
+// newReporter builds the Reporter for -format, writing structured output
+// (json/ndjson/sarif) to out.
+func newReporter(format string, out io.Writer) (Reporter, error) {
+	switch format {
+	case "", "text":
+		return &TextReporter{printer: printer}, nil
+	case "json":
+		return &JSONReporter{out: out}, nil
+	case "ndjson":
+		return &NDJSONReporter{out: out}, nil
+	case "sarif":
+		return &SARIFReporter{out: out}, nil
+	default:
+		return nil, fmt.Errorf("unknown -format %q (want text, json, ndjson or sarif)", format)
+	}
+}
+
+// TextReporter preserves JSReader's original colored terminal output.
+type TextReporter struct {
+	printer *SafePrinter
+}
+
+func (r *TextReporter) ReportResult(result Result) { r.printer.PrintResult(result) }
+func (r *TextReporter) Finish() error              { return nil }
+
+// JSONReporter buffers every finding and emits one JSON object at the end
+// of the run, as required for tools that expect a complete document.
+type JSONReporter struct {
+	mu       sync.Mutex
+	out      io.Writer
+	findings []Result
+}
+
+func (r *JSONReporter) ReportResult(result Result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.findings = append(r.findings, result)
+}
+
+func (r *JSONReporter) Finish() error {
+	doc := struct {
+		Tool     string   `json:"tool"`
+		Version  string   `json:"version"`
+		Findings []Result `json:"findings"`
+	}{
+		Tool:     "jsreader",
+		Version:  jsReaderVersion,
+		Findings: r.findings,
+	}
+
+	enc := json.NewEncoder(r.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// NDJSONReporter streams one Result per line as soon as it's discovered,
+// which long crawls need so a consumer isn't stuck waiting for EOF.
+type NDJSONReporter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func (r *NDJSONReporter) ReportResult(result Result) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		printer.PrintError("NDJSON", err.Error())
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fmt.Fprintln(r.out, string(data))
+}
+
+func (r *NDJSONReporter) Finish() error { return nil }
+
+// SARIFReporter emits a SARIF 2.1.0 log so findings can be ingested by
+// GitHub code scanning and similar DevSecOps dashboards.
+type SARIFReporter struct {
+	mu       sync.Mutex
+	out      io.Writer
+	findings []Result
+}
+
+func (r *SARIFReporter) ReportResult(result Result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.findings = append(r.findings, result)
+}
+
+func (r *SARIFReporter) Finish() error {
+	ruleIndex := make(map[string]int)
+	var rules []sarifRule
+	var results []sarifResult
+
+	for _, f := range r.findings {
+		ruleID := sarifRuleID(f.Type)
+		idx, ok := ruleIndex[ruleID]
+		if !ok {
+			idx = len(rules)
+			ruleIndex[ruleID] = idx
+			rules = append(rules, sarifRule{
+				ID:               ruleID,
+				Name:             f.Type,
+				ShortDescription: sarifText{Text: f.Type},
+			})
+		}
+
+		message := f.Details
+		if message == "" {
+			message = f.Type
+		}
+
+		results = append(results, sarifResult{
+			RuleID:    ruleID,
+			RuleIndex: idx,
+			Message:   sarifText{Text: message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: f.Source},
+				},
+			}},
+		})
+	}
+
+	doc := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:    "jsreader",
+				Version: jsReaderVersion,
+				Rules:   rules,
+			}},
+			Results: results,
+		}},
+	}
+
+	enc := json.NewEncoder(r.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+func sarifRuleID(resultType string) string {
+	return strings.ToLower(strings.ReplaceAll(resultType, " ", "-"))
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	ShortDescription sarifText `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Message   sarifText       `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
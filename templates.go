@@ -0,0 +1,339 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// PatternTemplate is a nuclei-style detection rule loaded from YAML. A
+// template matches one or more regexes against the JS source and, when
+// ContextRegex is set, upgrades a match to "high confidence" if the
+// surrounding code also matches it (see the Telegram bundled template).
+type PatternTemplate struct {
+	ID           string   `yaml:"id"`
+	Type         string   `yaml:"type"`
+	Severity     string   `yaml:"severity"`
+	Color        string   `yaml:"color"`
+	Details      string   `yaml:"details"`
+	Tags         []string `yaml:"tags"`
+	Regex        []string `yaml:"regex"`
+	ContextRegex string   `yaml:"context-regex"`
+	Exclude      []string `yaml:"exclude"`
+
+	compiledRegex   []*regexp.Regexp
+	compiledContext *regexp.Regexp
+	compiledExclude []*regexp.Regexp
+}
+
+// compile pre-compiles every regex declared on the template. It must be
+// called once after a template is parsed, before it is used to scan.
+func (t *PatternTemplate) compile() error {
+	if t.ID == "" {
+		return fmt.Errorf("template is missing an id")
+	}
+	if len(t.Regex) == 0 {
+		return fmt.Errorf("template %s: at least one regex entry is required", t.ID)
+	}
+
+	for _, pattern := range t.Regex {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("template %s: invalid regex %q: %w", t.ID, pattern, err)
+		}
+		t.compiledRegex = append(t.compiledRegex, re)
+	}
+
+	if t.ContextRegex != "" {
+		re, err := regexp.Compile(t.ContextRegex)
+		if err != nil {
+			return fmt.Errorf("template %s: invalid context-regex %q: %w", t.ID, t.ContextRegex, err)
+		}
+		t.compiledContext = re
+	}
+
+	for _, pattern := range t.Exclude {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("template %s: invalid exclude regex %q: %w", t.ID, pattern, err)
+		}
+		t.compiledExclude = append(t.compiledExclude, re)
+	}
+
+	return nil
+}
+
+// excluded reports whether match is filtered out by the template's own
+// exclude list.
+func (t *PatternTemplate) excluded(match string) bool {
+	for _, re := range t.compiledExclude {
+		if re.MatchString(strings.ToLower(match)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesFilter implements the -tag flag, accepting "severity:<level>" or
+// "tags:<tag>" filter expressions. An empty filter always matches.
+func (t *PatternTemplate) matchesFilter(filter string) bool {
+	if filter == "" {
+		return true
+	}
+
+	key, value, ok := strings.Cut(filter, ":")
+	if !ok {
+		return true
+	}
+
+	switch strings.ToLower(key) {
+	case "severity":
+		return strings.EqualFold(t.Severity, value)
+	case "tag", "tags":
+		for _, tag := range t.Tags {
+			if strings.EqualFold(tag, value) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// loadTemplates loads PatternTemplates from every *.yaml/*.yml file in dir.
+// If dir does not exist, JSReader falls back to its bundled defaults so the
+// tool keeps working without a patterns/ directory on disk.
+func loadTemplates(dir string) ([]*PatternTemplate, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return defaultTemplates(), nil
+		}
+		return nil, fmt.Errorf("reading templates directory: %w", err)
+	}
+
+	var templates []*PatternTemplate
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".yaml") && !strings.HasSuffix(name, ".yml") {
+			continue
+		}
+
+		t, err := parseTemplateFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("parsing template %s: %w", name, err)
+		}
+		if err := t.compile(); err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+
+	if len(templates) == 0 {
+		return defaultTemplates(), nil
+	}
+	return templates, nil
+}
+
+// filterTemplates narrows templates down to those matching the -tag filter.
+func filterTemplates(templates []*PatternTemplate, filter string) []*PatternTemplate {
+	if filter == "" {
+		return templates
+	}
+
+	var filtered []*PatternTemplate
+	for _, t := range templates {
+		if t.matchesFilter(filter) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// parseTemplateFile reads a single YAML template file. JSReader only needs
+// a small subset of YAML (top-level scalars plus "key:" list blocks), so we
+// parse that subset directly rather than pulling in a full YAML library.
+func parseTemplateFile(path string) (*PatternTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &PatternTemplate{}
+	var list *[]string
+
+	for _, rawLine := range strings.Split(string(data), "\n") {
+		line := strings.TrimRight(rawLine, "\r")
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- ") {
+			if list == nil {
+				return nil, fmt.Errorf("unexpected list item %q outside of a list field", trimmed)
+			}
+			*list = append(*list, unquoteYAML(strings.TrimPrefix(trimmed, "- ")))
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return nil, fmt.Errorf("malformed line %q", trimmed)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		list = nil
+
+		switch key {
+		case "id":
+			t.ID = unquoteYAML(value)
+		case "type":
+			t.Type = unquoteYAML(value)
+		case "severity":
+			t.Severity = unquoteYAML(value)
+		case "color":
+			t.Color = unquoteYAML(value)
+		case "details":
+			t.Details = unquoteYAML(value)
+		case "context-regex":
+			t.ContextRegex = unquoteYAML(value)
+		case "tags":
+			if value != "" {
+				t.Tags = append(t.Tags, unquoteYAML(value))
+			} else {
+				list = &t.Tags
+			}
+		case "regex":
+			if value != "" {
+				t.Regex = append(t.Regex, unquoteYAML(value))
+			} else {
+				list = &t.Regex
+			}
+		case "exclude":
+			if value != "" {
+				t.Exclude = append(t.Exclude, unquoteYAML(value))
+			} else {
+				list = &t.Exclude
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// unquoteYAML strips a single matching pair of surrounding quotes, which is
+// all the quoting JSReader's template files are expected to use.
+func unquoteYAML(value string) string {
+	if len(value) >= 2 {
+		if (value[0] == '\'' && value[len(value)-1] == '\'') ||
+			(value[0] == '"' && value[len(value)-1] == '"') {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
+
+// defaultTemplates returns JSReader's bundled detections, used whenever
+// -templates points at a missing directory. These mirror patterns/*.yaml
+// so the binary keeps working even when shipped without that directory.
+func defaultTemplates() []*PatternTemplate {
+	templates := []*PatternTemplate{
+		{
+			ID:       "s3-bucket",
+			Type:     "S3 Bucket",
+			Severity: "high",
+			Color:    "red",
+			Details:  "Potential public S3 bucket - check permissions",
+			Tags:     []string{"aws", "storage"},
+			Regex:    []string{`https?://[a-zA-Z0-9.-]*\.?s3[.-][a-z0-9-]*\.amazonaws\.com[^\s"']*`},
+		},
+		{
+			ID:       "firebase-rtdb",
+			Type:     "Firebase URL",
+			Severity: "high",
+			Color:    "yellow",
+			Details:  "Firebase service - check security rules",
+			Tags:     []string{"firebase"},
+			Regex:    []string{`https?://[a-zA-Z0-9-]+\.firebaseio\.com[^\s"']*`},
+		},
+		{
+			ID:       "firebase-storage",
+			Type:     "Firebase Storage",
+			Severity: "high",
+			Color:    "yellow",
+			Details:  "Firebase service - check security rules",
+			Tags:     []string{"firebase"},
+			Regex:    []string{`https?://firebasestorage\.googleapis\.com[^\s"']*`},
+		},
+		{
+			ID:       "firebase-api",
+			Type:     "Firebase API",
+			Severity: "high",
+			Color:    "yellow",
+			Details:  "Firebase service - check security rules",
+			Tags:     []string{"firebase"},
+			Regex:    []string{`https?://[a-zA-Z0-9-]+\.firebaseapp\.com[^\s"']*`},
+		},
+		{
+			ID:       "api-endpoint",
+			Type:     "API Endpoint",
+			Severity: "medium",
+			Color:    "green",
+			Details:  "API endpoint - investigate available methods",
+			Tags:     []string{"api"},
+			Regex:    []string{`https?://[a-zA-Z0-9.-]+/(v[0-9]+/|api/)[a-zA-Z0-9./_-]*`},
+		},
+		{
+			ID:       "graphql-endpoint",
+			Type:     "GraphQL",
+			Severity: "medium",
+			Color:    "cyan",
+			Details:  "GraphQL endpoint discovered",
+			Tags:     []string{"api", "graphql"},
+			Regex:    []string{`https?://[a-zA-Z0-9.-]+/(graphql|gql)[^\s"']*`},
+		},
+		{
+			ID:       "auth-endpoint",
+			Type:     "Auth Endpoint",
+			Severity: "medium",
+			Color:    "purple",
+			Details:  "Authentication endpoint - check for vulnerabilities",
+			Tags:     []string{"api", "auth"},
+			Regex:    []string{`https?://[a-zA-Z0-9.-]+/(auth|oauth|token|login|register|user|admin)[^\s"']*`},
+		},
+		{
+			ID:           "telegram-token",
+			Type:         "Telegram Token",
+			Severity:     "high",
+			Color:        "orange",
+			Details:      "Telegram Bot API token - check if it's exposed",
+			Tags:         []string{"telegram", "secret"},
+			Regex:        []string{`[0-9]{8,10}:[a-zA-Z0-9_-]{35}`},
+			ContextRegex: `(bot|token|api|key)[\s]*[=:][\s]*["']([0-9]{8,10}:[a-zA-Z0-9_-]{35})["']`,
+		},
+		{
+			ID:       "api-subdomain",
+			Type:     "API Subdomain",
+			Severity: "low",
+			Color:    "teal",
+			Details:  "Dedicated API subdomain - investigate available endpoints",
+			Tags:     []string{"api"},
+			Regex:    []string{`https?://(api|api-[a-zA-Z0-9]+)\.([a-zA-Z0-9-]+\.)+[a-zA-Z]{2,}[^\s"']*`},
+		},
+	}
+
+	for _, t := range templates {
+		if err := t.compile(); err != nil {
+			panic(fmt.Sprintf("jsreader: bundled template %s failed to compile: %v", t.ID, err))
+		}
+	}
+	return templates
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTemplateFile(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing template file: %v", err)
+	}
+	return path
+}
+
+func TestParseTemplateFileQuotedListItems(t *testing.T) {
+	path := writeTemplateFile(t, `id: test-template
+type: Test
+severity: high
+details: a test template
+tags:
+  - "tag-one"
+  - 'tag-two'
+regex:
+  - "foo[0-9]+"
+`)
+
+	tmpl, err := parseTemplateFile(path)
+	if err != nil {
+		t.Fatalf("parseTemplateFile: %v", err)
+	}
+
+	if tmpl.ID != "test-template" || tmpl.Type != "Test" {
+		t.Fatalf("unexpected template: %+v", tmpl)
+	}
+	if len(tmpl.Tags) != 2 || tmpl.Tags[0] != "tag-one" || tmpl.Tags[1] != "tag-two" {
+		t.Fatalf("unexpected tags: %v", tmpl.Tags)
+	}
+	if len(tmpl.Regex) != 1 || tmpl.Regex[0] != "foo[0-9]+" {
+		t.Fatalf("unexpected regex: %v", tmpl.Regex)
+	}
+}
+
+func TestParseTemplateFileContextRegex(t *testing.T) {
+	path := writeTemplateFile(t, `id: telegram-token
+type: Telegram Token
+details: telegram bot token
+regex:
+  - "[0-9]{8,10}:[a-zA-Z0-9_-]{35}"
+context-regex: (bot|token)[\s]*[=:][\s]*["']([0-9]{8,10}:[a-zA-Z0-9_-]{35})["']
+`)
+
+	tmpl, err := parseTemplateFile(path)
+	if err != nil {
+		t.Fatalf("parseTemplateFile: %v", err)
+	}
+	if tmpl.ContextRegex == "" {
+		t.Fatalf("expected context-regex to be set")
+	}
+
+	if err := tmpl.compile(); err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if tmpl.compiledContext == nil {
+		t.Fatalf("expected compiledContext to be set after compile")
+	}
+}
+
+func TestParseTemplateFileMultiKey(t *testing.T) {
+	path := writeTemplateFile(t, `id: multi-key
+type: Multi
+severity: medium
+color: blue
+details: a multi-key template
+tags:
+  - one
+  - two
+regex:
+  - "a+"
+  - "b+"
+exclude:
+  - "localhost"
+`)
+
+	tmpl, err := parseTemplateFile(path)
+	if err != nil {
+		t.Fatalf("parseTemplateFile: %v", err)
+	}
+
+	if tmpl.Severity != "medium" || tmpl.Color != "blue" {
+		t.Fatalf("unexpected template: %+v", tmpl)
+	}
+	if len(tmpl.Regex) != 2 || tmpl.Regex[0] != "a+" || tmpl.Regex[1] != "b+" {
+		t.Fatalf("unexpected regex: %v", tmpl.Regex)
+	}
+	if len(tmpl.Exclude) != 1 || tmpl.Exclude[0] != "localhost" {
+		t.Fatalf("unexpected exclude: %v", tmpl.Exclude)
+	}
+}
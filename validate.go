@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	validateEnabled bool
+	validateRPS     float64
+
+	validateClient      *http.Client
+	validateLimiter     *rateLimiter
+	validateClientSetup sync.Once
+)
+
+// rateLimiter is a minimal token-less limiter: it simply ensures that
+// successive Wait() calls are spaced at least 1/rps apart, so -validate
+// never hammers a target harder than the operator asked for.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	last     time.Time
+}
+
+func newRateLimiter(rps float64) *rateLimiter {
+	if rps <= 0 {
+		rps = 1
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / rps)}
+}
+
+func (r *rateLimiter) Wait() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if wait := r.interval - time.Since(r.last); wait > 0 {
+		time.Sleep(wait)
+	}
+	r.last = time.Now()
+}
+
+// setupValidation initializes the shared HTTP client and rate limiter used
+// by every Validator. It is safe to call repeatedly; only the first call
+// (per process) takes effect.
+func setupValidation() {
+	validateClientSetup.Do(func() {
+		validateClient = &http.Client{Timeout: 10 * time.Second}
+		validateLimiter = newRateLimiter(validateRPS)
+	})
+}
+
+// Validator performs a safe, read-only verification call for one finding
+// type and returns a short human-readable outcome to store on
+// Result.Validation. New checks are added by implementing Validator and
+// registering it in the validators map below.
+type Validator interface {
+	Validate(client *http.Client, result Result) string
+}
+
+var validators = map[string]Validator{
+	"Telegram Token":   telegramValidator{},
+	"S3 Bucket":        s3Validator{},
+	"Firebase URL":     firebaseRTDBValidator{},
+	"Firebase Storage": firebaseStorageValidator{},
+	"API Endpoint":     genericEndpointValidator{},
+	"Auth Endpoint":    genericEndpointValidator{},
+	"GraphQL":          genericEndpointValidator{},
+}
+
+// validateResult runs the registered Validator for result.Type, if any,
+// rate-limited through the shared validate client.
+func validateResult(result Result) string {
+	v, ok := validators[result.Type]
+	if !ok {
+		return ""
+	}
+
+	setupValidation()
+	validateLimiter.Wait()
+	return v.Validate(validateClient, result)
+}
+
+type telegramValidator struct{}
+
+func (telegramValidator) Validate(client *http.Client, result Result) string {
+	resp, err := client.Get(fmt.Sprintf("https://api.telegram.org/bot%s/getMe", result.URL))
+	if err != nil {
+		return "CHECK-FAILED: " + err.Error()
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "INVALID"
+	}
+	return "VALID bot - " + string(body)
+}
+
+// bucketRootURL discards any path, query or fragment from rawURL and
+// returns just its scheme+host, so a Validator can append its own probe
+// path instead of assuming rawURL - which may be a detected object/file
+// reference, not the bucket/database root - already points there.
+func bucketRootURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
+
+type s3Validator struct{}
+
+func (s3Validator) Validate(client *http.Client, result Result) string {
+	root, err := bucketRootURL(result.URL)
+	if err != nil {
+		return "CHECK-FAILED: " + err.Error()
+	}
+	resp, err := client.Get(root + "/?list-type=2")
+	if err != nil {
+		return "CHECK-FAILED: " + err.Error()
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	switch {
+	case resp.StatusCode == http.StatusOK && strings.Contains(string(body), "<ListBucketResult"):
+		return "PUBLIC-LIST"
+	case resp.StatusCode == http.StatusForbidden:
+		return "PUBLIC-READ"
+	default:
+		return "PRIVATE"
+	}
+}
+
+type firebaseRTDBValidator struct{}
+
+func (firebaseRTDBValidator) Validate(client *http.Client, result Result) string {
+	root, err := bucketRootURL(result.URL)
+	if err != nil {
+		return "CHECK-FAILED: " + err.Error()
+	}
+	resp, err := client.Get(root + "/.json")
+	if err != nil {
+		return "CHECK-FAILED: " + err.Error()
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return "PUBLIC-READABLE"
+	}
+	return "PRIVATE"
+}
+
+type firebaseStorageValidator struct{}
+
+func (firebaseStorageValidator) Validate(client *http.Client, result Result) string {
+	root, err := bucketRootURL(result.URL)
+	if err != nil {
+		return "CHECK-FAILED: " + err.Error()
+	}
+	resp, err := client.Get(root + "/o?maxResults=1")
+	if err != nil {
+		return "CHECK-FAILED: " + err.Error()
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return "PUBLIC-READABLE"
+	}
+	return "PRIVATE"
+}
+
+// genericEndpointValidator issues a HEAD request against API, Auth and
+// GraphQL endpoint findings and records the status code plus server
+// fingerprint.
+type genericEndpointValidator struct{}
+
+func (genericEndpointValidator) Validate(client *http.Client, result Result) string {
+	req, err := http.NewRequest(http.MethodHead, result.URL, nil)
+	if err != nil {
+		return "CHECK-FAILED: " + err.Error()
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "CHECK-FAILED: " + err.Error()
+	}
+	defer resp.Body.Close()
+
+	outcome := fmt.Sprintf("HTTP %d", resp.StatusCode)
+	if server := resp.Header.Get("Server"); server != "" {
+		outcome += " Server=" + server
+	}
+	if powered := resp.Header.Get("X-Powered-By"); powered != "" {
+		outcome += " X-Powered-By=" + powered
+	}
+	return outcome
+}